@@ -0,0 +1,130 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// HealthSpec configures liveness/readiness gating for a manifest. It patches the pod spec's
+// livenessProbe/readinessProbe values before the manifest is applied, and gates the manifest's
+// SetValuesFunc/SetValuesHelmFunc on ReadyFunc succeeding within the configured retry budget, so a slow
+// component (e.g. geth syncing) doesn't wedge the whole test with a single failed probe.
+type HealthSpec struct {
+	InitialDelaySeconds int32
+	PeriodSeconds       int32
+	FailureThreshold    int32
+	SuccessThreshold    int32
+	TimeoutSeconds      int32
+
+	// ReadyFunc is an app-level check run on top of the kubelet probe, e.g. "chainlink /health returns 200"
+	// or "explorer admin seeded". A nil ReadyFunc skips the app-level gate and relies on the pod probe alone.
+	ReadyFunc func(ctx context.Context) error
+}
+
+// probeValues returns the livenessProbe/readinessProbe values this spec resolves to, in the shape the
+// deployer merges into a manifest's values map before applying its templates/chart.
+func (h HealthSpec) probeValues() map[string]interface{} {
+	return map[string]interface{}{
+		"initialDelaySeconds": h.InitialDelaySeconds,
+		"periodSeconds":       h.PeriodSeconds,
+		"failureThreshold":    h.FailureThreshold,
+		"successThreshold":    h.SuccessThreshold,
+		"timeoutSeconds":      h.TimeoutSeconds,
+	}
+}
+
+// WaitReady polls ReadyFunc until it succeeds, FailureThreshold attempts have failed, or ctx is done,
+// whichever comes first. A nil ReadyFunc is treated as immediately ready.
+func (h HealthSpec) WaitReady(ctx context.Context, component string) error {
+	if h.ReadyFunc == nil {
+		return nil
+	}
+
+	threshold := h.FailureThreshold
+	if threshold <= 0 {
+		threshold = 1
+	}
+	period := time.Duration(h.PeriodSeconds) * time.Second
+	if period <= 0 {
+		period = time.Second
+	}
+
+	var lastErr error
+	for attempt := int32(1); attempt <= threshold; attempt++ {
+		lastErr = h.ReadyFunc(ctx)
+		if lastErr == nil {
+			return nil
+		}
+		log.Debug().
+			Str("component", component).
+			Int32("attempt", attempt).
+			Int32("failureThreshold", threshold).
+			Err(lastErr).
+			Msg("readiness check failed, retrying")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(period):
+		}
+	}
+	return errors.Wrapf(lastErr, "%s did not become ready after %d attempts", component, threshold)
+}
+
+// patchHealthValues merges spec's probe values into manifest's values map under the livenessProbe/
+// readinessProbe keys the deployment templates/charts read. Every builder that sets manifest.Health calls
+// this right after, so the probe values always reach the rendered pod spec alongside it.
+func patchHealthValues(values map[string]interface{}, spec HealthSpec) {
+	values["livenessProbe"] = spec.probeValues()
+	values["readinessProbe"] = spec.probeValues()
+}
+
+// DefaultChainlinkHealthSpec checks the node's keys API, which only returns 200 once the node has finished
+// booting and unlocking its keystore.
+func DefaultChainlinkHealthSpec(localURL func() string) HealthSpec {
+	return HealthSpec{
+		InitialDelaySeconds: 5,
+		PeriodSeconds:       5,
+		FailureThreshold:    20,
+		SuccessThreshold:    1,
+		TimeoutSeconds:      5,
+		ReadyFunc: func(ctx context.Context) error {
+			return httpGetOK(ctx, fmt.Sprintf("%s/v2/keys/eth", localURL()))
+		},
+	}
+}
+
+// DefaultPostgresHealthSpec shells out to pg_isready, the standard way to check a postgres instance is
+// accepting connections before pointing chainlink nodes at it. execInPod runs a command inside the postgres
+// pod (normally manifest.ExecuteInPod bound to its pod name) and should return an error if the command's
+// exit code is non-zero.
+func DefaultPostgresHealthSpec(execInPod func(ctx context.Context, cmd []string) error) HealthSpec {
+	return HealthSpec{
+		InitialDelaySeconds: 2,
+		PeriodSeconds:       3,
+		FailureThreshold:    10,
+		SuccessThreshold:    1,
+		TimeoutSeconds:      3,
+		ReadyFunc: func(ctx context.Context) error {
+			return execInPod(ctx, []string{"pg_isready", "-h", "127.0.0.1"})
+		},
+	}
+}
+
+// DefaultGethHealthSpec polls eth_syncing so a geth node mid-sync (which can take minutes) is given a
+// generous failure budget instead of wedging the deploy on the first failed probe.
+func DefaultGethHealthSpec(rpcURL func() string) HealthSpec {
+	return HealthSpec{
+		InitialDelaySeconds: 5,
+		PeriodSeconds:       10,
+		FailureThreshold:    60,
+		SuccessThreshold:    1,
+		TimeoutSeconds:      10,
+		ReadyFunc: func(ctx context.Context) error {
+			return rpcCall(ctx, rpcURL(), "eth_syncing")
+		},
+	}
+}
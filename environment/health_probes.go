@@ -0,0 +1,74 @@
+package environment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// httpGetOK is a small ReadyFunc building block: it succeeds only on a 2xx response from url.
+func httpGetOK(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// rpcResult calls a JSON-RPC method and returns its raw "result" field, failing on a non-2xx response or a
+// response with no result.
+func rpcResult(ctx context.Context, url, method string) (json.RawMessage, error) {
+	body := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":%q,"params":[]}`, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBufferString(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error calling %s on %s", method, url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s returned status %d", url, method, resp.StatusCode)
+	}
+	var rpcResp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return nil, errors.Wrapf(err, "error decoding %s response from %s", method, url)
+	}
+	if rpcResp.Error != nil {
+		return nil, fmt.Errorf("%s %s returned error: %s", url, method, rpcResp.Error.Message)
+	}
+	return rpcResp.Result, nil
+}
+
+// rpcCall is a ReadyFunc building block for JSON-RPC backed components: it succeeds only once method's result
+// is the literal JSON value false, which is how eth_syncing reports "fully synced" (it returns an object with
+// progress fields while syncing). A slow geth boot is expected to fail this for a while before sync completes.
+func rpcCall(ctx context.Context, url, method string) error {
+	result, err := rpcResult(ctx, url, method)
+	if err != nil {
+		return err
+	}
+	if string(result) != "false" {
+		return fmt.Errorf("%s %s has not finished syncing yet: %s", url, method, result)
+	}
+	return nil
+}
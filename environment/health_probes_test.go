@@ -0,0 +1,40 @@
+package environment
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rpcTestServer(t *testing.T, result string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + result + `}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRpcCall(t *testing.T) {
+	t.Run("succeeds once eth_syncing reports false", func(t *testing.T) {
+		server := rpcTestServer(t, "false")
+		assert.NoError(t, rpcCall(context.Background(), server.URL, "eth_syncing"))
+	})
+
+	t.Run("fails while eth_syncing still reports progress", func(t *testing.T) {
+		server := rpcTestServer(t, `{"startingBlock":"0x0","currentBlock":"0x1","highestBlock":"0x64"}`)
+		assert.Error(t, rpcCall(context.Background(), server.URL, "eth_syncing"))
+	})
+
+	t.Run("fails on a non-2xx response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		t.Cleanup(server.Close)
+		assert.Error(t, rpcCall(context.Background(), server.URL, "eth_syncing"))
+	})
+}
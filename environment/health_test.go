@@ -0,0 +1,101 @@
+package environment
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitReady(t *testing.T) {
+	t.Run("nil ReadyFunc is immediately ready", func(t *testing.T) {
+		spec := HealthSpec{FailureThreshold: 3, PeriodSeconds: 0}
+		assert.NoError(t, spec.WaitReady(context.Background(), "noop"))
+	})
+
+	t.Run("succeeds on the first attempt", func(t *testing.T) {
+		calls := 0
+		spec := HealthSpec{
+			FailureThreshold: 3,
+			PeriodSeconds:    0,
+			ReadyFunc: func(ctx context.Context) error {
+				calls++
+				return nil
+			},
+		}
+		assert.NoError(t, spec.WaitReady(context.Background(), "component"))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("retries until ReadyFunc succeeds within the failure threshold", func(t *testing.T) {
+		calls := 0
+		spec := HealthSpec{
+			FailureThreshold: 3,
+			PeriodSeconds:    0,
+			ReadyFunc: func(ctx context.Context) error {
+				calls++
+				if calls < 3 {
+					return errors.New("not ready yet")
+				}
+				return nil
+			},
+		}
+		assert.NoError(t, spec.WaitReady(context.Background(), "component"))
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("gives up and wraps the last error once the failure threshold is exhausted", func(t *testing.T) {
+		calls := 0
+		spec := HealthSpec{
+			FailureThreshold: 3,
+			PeriodSeconds:    0,
+			ReadyFunc: func(ctx context.Context) error {
+				calls++
+				return errors.New("still failing")
+			},
+		}
+		err := spec.WaitReady(context.Background(), "component")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "component did not become ready after 3 attempts")
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("stops early when ctx is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		calls := 0
+		spec := HealthSpec{
+			FailureThreshold: 5,
+			PeriodSeconds:    1,
+			ReadyFunc: func(ctx context.Context) error {
+				calls++
+				return errors.New("not ready")
+			},
+		}
+		err := spec.WaitReady(ctx, "component")
+		require.Error(t, err)
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestPatchHealthValues(t *testing.T) {
+	values := map[string]interface{}{}
+	spec := HealthSpec{
+		InitialDelaySeconds: 5,
+		PeriodSeconds:       10,
+		FailureThreshold:    20,
+		SuccessThreshold:    1,
+		TimeoutSeconds:      5,
+	}
+	patchHealthValues(values, spec)
+
+	for _, key := range []string{"livenessProbe", "readinessProbe"} {
+		probe, ok := values[key].(map[string]interface{})
+		require.True(t, ok, "%s should be a probe map", key)
+		assert.Equal(t, int32(5), probe["initialDelaySeconds"])
+		assert.Equal(t, int32(20), probe["failureThreshold"])
+	}
+}
@@ -0,0 +1,111 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	networkingV1 "k8s.io/api/networking/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/smartcontractkit/integrations-framework/config"
+	"github.com/smartcontractkit/integrations-framework/environment/netpol"
+)
+
+// NetworkPolicyMode selects what WithNetworkPolicies does with the policies it computes once a K8sEnvSpecs
+// has finished deploying.
+type NetworkPolicyMode int
+
+const (
+	// NetworkPolicyDryRun only logs the computed NetworkPolicy YAML for review, useful for a CI hardening pass.
+	NetworkPolicyDryRun NetworkPolicyMode = iota
+	// NetworkPolicyEnforce computes the policy set like NetworkPolicyDryRun but, instead of applying it there
+	// and then (a K8sEnvSpecInit has no Kubernetes client to apply with — that only exists once the returned
+	// specs are actually deployed), stashes it under envName for ApplyNetworkPolicies to apply once a real
+	// client is available.
+	NetworkPolicyEnforce
+	// NetworkPolicyValidate computes the policy set like NetworkPolicyDryRun but, instead of applying it,
+	// keeps it around under envName so a test can call ValidateObservedConnections once it has driven real
+	// traffic, failing if anything it observed would have been blocked.
+	NetworkPolicyValidate
+)
+
+var (
+	computedPoliciesMu sync.Mutex
+	computedPolicies   = map[string][]*networkingV1.NetworkPolicy{}
+)
+
+// WithNetworkPolicies wraps a K8sEnvSpecInit so that once it builds the environment's specs, the manifest
+// graph is statically analyzed and a NetworkPolicy set locking pod-to-pod traffic down to the edges actually
+// used is computed. In NetworkPolicyDryRun mode the YAML is only logged; in NetworkPolicyEnforce mode it's
+// stashed for ApplyNetworkPolicies to apply once the environment has a deployed namespace and client; in
+// NetworkPolicyValidate mode it's stashed for ValidateObservedConnections, so a test can assert real traffic
+// would have been allowed before enforcing it.
+func WithNetworkPolicies(init K8sEnvSpecInit, mode NetworkPolicyMode) K8sEnvSpecInit {
+	return func(networkConfig *config.NetworkConfig) (string, K8sEnvSpecs) {
+		envName, specs := init(networkConfig)
+
+		graph, err := netpol.BuildGraph(specs)
+		if err != nil {
+			log.Err(err).Msg("error building network policy graph, network policies will not be computed")
+			return envName, specs
+		}
+		policies := netpol.Synthesize(graph, envName)
+
+		switch mode {
+		case NetworkPolicyDryRun:
+			yamlDump, err := netpol.DumpYAML(policies)
+			if err != nil {
+				log.Err(err).Msg("error rendering computed network policies to YAML")
+				return envName, specs
+			}
+			log.Info().Str("policies", yamlDump).Msg("computed network policies (dry-run, not applied)")
+		case NetworkPolicyValidate:
+			stageComputedPolicies(envName, policies)
+			log.Info().Str("env", envName).Int("policyCount", len(policies)).
+				Msg("computed network policies staged for validation, call ValidateObservedConnections once traffic has run")
+		case NetworkPolicyEnforce:
+			stageComputedPolicies(envName, policies)
+			log.Info().Str("env", envName).Int("policyCount", len(policies)).
+				Msg("computed network policies staged for enforcement, call ApplyNetworkPolicies once the environment is deployed")
+		}
+
+		return envName, specs
+	}
+}
+
+func stageComputedPolicies(envName string, policies []*networkingV1.NetworkPolicy) {
+	computedPoliciesMu.Lock()
+	computedPolicies[envName] = policies
+	computedPoliciesMu.Unlock()
+}
+
+// ValidateObservedConnections fails with a descriptive error on the first connection in observed that the
+// NetworkPolicy set computed for envName (under NetworkPolicyValidate) would not allow. Call it after a test
+// has driven its real traffic, before switching the environment over to NetworkPolicyEnforce.
+func ValidateObservedConnections(envName string, observed []netpol.ObservedConnection) error {
+	policies, ok := computedPoliciesFor(envName)
+	if !ok {
+		return fmt.Errorf("no network policies were computed for %q, was it built with NetworkPolicyValidate?", envName)
+	}
+	return netpol.Validate(policies, observed)
+}
+
+// ApplyNetworkPolicies applies the NetworkPolicy set computed for envName (under NetworkPolicyEnforce) to
+// namespace using client. Call it once the environment built with NetworkPolicyEnforce has actually been
+// deployed and a client for its namespace is available.
+func ApplyNetworkPolicies(ctx context.Context, client kubernetes.Interface, namespace, envName string) error {
+	policies, ok := computedPoliciesFor(envName)
+	if !ok {
+		return fmt.Errorf("no network policies were computed for %q, was it built with NetworkPolicyEnforce?", envName)
+	}
+	return netpol.Apply(ctx, client, namespace, policies)
+}
+
+func computedPoliciesFor(envName string) ([]*networkingV1.NetworkPolicy, bool) {
+	computedPoliciesMu.Lock()
+	defer computedPoliciesMu.Unlock()
+	policies, ok := computedPolicies[envName]
+	return policies, ok
+}
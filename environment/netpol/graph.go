@@ -0,0 +1,203 @@
+// Package netpol statically analyzes a deployed environment's manifest graph and synthesizes Kubernetes
+// NetworkPolicy objects that lock pod-to-pod traffic down to only the edges the environment actually uses.
+package netpol
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/integrations-framework/environment"
+)
+
+// PodSelector identifies the pods an Edge's traffic originates from or terminates at.
+type PodSelector struct {
+	// ID is the manifest ID (K8sEnvResource.ID()) the selector was derived from, kept for readable policy names.
+	ID     string
+	Labels map[string]string
+}
+
+// Edge is one allowed connection discovered in the manifest graph: From may reach To on Port.
+type Edge struct {
+	From PodSelector
+	To   PodSelector
+	Port int32
+}
+
+// Graph is the full set of edges discovered across a deployed environment.
+type Graph struct {
+	Edges []Edge
+}
+
+// ManifestInfo is the subset of a deployed K8sEnvResource the graph builder needs: its identity and its
+// resolved values map (the convention this repo's SetValuesFunc/SetValuesHelmFunc implementations use to
+// publish a dependency's in-cluster address under keys like "clusterURL").
+type ManifestInfo struct {
+	ID     string
+	Labels map[string]string
+	Values map[string]interface{}
+}
+
+// GroupInfo is the subset of a K8sManifestGroup the graph builder needs: its own manifests, plus any
+// aggregate values the group itself publishes (e.g. getBasicDependencyGroup's "dbURLs" fan-out of every
+// postgres manifest's clusterURL).
+type GroupInfo struct {
+	Manifests []ManifestInfo
+	Values    map[string]interface{}
+}
+
+// BuildGraph walks specs and extracts the provider/consumer relationship this repo actually wires: by the
+// addNetworkManifestToDependencyGroup convention, specs[0] is the dependency group (adapter, postgres DBs,
+// simulated chain) and any remaining groups are chainlink node groups that consume it — chainlink nodes are
+// configured from the dependency group's values, never the other way around, so "who can reach whom" is a
+// group-to-group relationship, not something recoverable by diffing one manifest's own values against
+// another's (every component only ever publishes its *own* clusterURL into its *own* values map).
+func BuildGraph(specs environment.K8sEnvSpecs) (*Graph, error) {
+	if len(specs) == 0 {
+		return &Graph{}, nil
+	}
+
+	groups := make([]GroupInfo, 0, len(specs))
+	for _, group := range specs {
+		info := GroupInfo{Values: group.Values()}
+		for _, manifest := range group.Manifests() {
+			info.Manifests = append(info.Manifests, ManifestInfo{
+				ID:     manifest.ID(),
+				Labels: map[string]string{"app": manifest.ID()},
+				Values: manifest.Values(),
+			})
+		}
+		groups = append(groups, info)
+	}
+
+	return BuildGraphFromGroups(groups[0], groups[1:])
+}
+
+// BuildGraphFromGroups is the pure core of BuildGraph: providerGroup's manifests (and its own aggregate
+// values) are the edge destinations, every manifest across consumerGroups is a potential source. It takes
+// plain data so it can be exercised without a deployed environment.
+func BuildGraphFromGroups(providerGroup GroupInfo, consumerGroups []GroupInfo) (*Graph, error) {
+	var consumers []PodSelector
+	for _, group := range consumerGroups {
+		for _, manifest := range group.Manifests {
+			consumers = append(consumers, PodSelector{ID: manifest.ID, Labels: manifest.Labels})
+		}
+	}
+	// A dependency-only environment (no chainlink group) has nothing to lock down beyond the DNS egress
+	// every policy already allows.
+	if len(consumers) == 0 {
+		return &Graph{}, nil
+	}
+
+	graph := &Graph{}
+	addEdges := func(provider PodSelector, raw interface{}, sourceDesc string) error {
+		ports, err := portsIn(raw)
+		if err != nil {
+			return errors.Wrapf(err, "error parsing %s as a cluster URL", sourceDesc)
+		}
+		for _, port := range ports {
+			for _, consumer := range consumers {
+				graph.Edges = append(graph.Edges, Edge{From: consumer, To: provider, Port: port})
+			}
+		}
+		return nil
+	}
+
+	for _, manifest := range providerGroup.Manifests {
+		provider := PodSelector{ID: manifest.ID, Labels: manifest.Labels}
+		for key, raw := range manifest.Values {
+			if !isClusterURLKey(key) {
+				continue
+			}
+			if err := addEdges(provider, raw, manifest.ID+"."+key); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Aggregate values the group publishes itself (e.g. dbURLs) describe the same providers again; harmless
+	// to re-derive since Synthesize only cares about the deduplicated port/selector pairs it produces.
+	for key, raw := range providerGroup.Values {
+		if !isClusterURLKey(key) {
+			continue
+		}
+		owner, ok := ownerFor(key, providerGroup)
+		if !ok {
+			continue
+		}
+		if err := addEdges(owner, raw, "dependency group."+key); err != nil {
+			return nil, err
+		}
+	}
+
+	return graph, nil
+}
+
+func isClusterURLKey(key string) bool {
+	return key == "clusterURL" || key == "dbURLs"
+}
+
+// ownerFor resolves which provider manifest a group-level aggregate value (like "dbURLs") actually belongs
+// to, so its edges are attributed to the right pod selector rather than the group as a whole. "dbURLs" is
+// always postgres in this repo's convention (see getBasicDependencyGroup).
+func ownerFor(key string, providerGroup GroupInfo) (PodSelector, bool) {
+	if key != "dbURLs" {
+		return PodSelector{}, false
+	}
+	for _, manifest := range providerGroup.Manifests {
+		if len(manifest.ID) >= len("postgres") && manifest.ID[:len("postgres")] == "postgres" {
+			return PodSelector{ID: manifest.ID, Labels: manifest.Labels}, true
+		}
+	}
+	return PodSelector{}, false
+}
+
+// portsIn extracts every port referenced by a clusterURL-shaped value, which is either a single URL string
+// or the TemplateValuesArray fan-out shape used for values like postgres.dbURLs.
+func portsIn(raw interface{}) ([]int32, error) {
+	switch v := raw.(type) {
+	case string:
+		port, ok, err := portIn(v)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, nil
+		}
+		return []int32{port}, nil
+	case *environment.TemplateValuesArray:
+		var ports []int32
+		for _, item := range v.Values {
+			urlStr, ok := item.(string)
+			if !ok {
+				continue
+			}
+			port, ok, err := portIn(urlStr)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				ports = append(ports, port)
+			}
+		}
+		return ports, nil
+	default:
+		return nil, nil
+	}
+}
+
+func portIn(rawURL string) (int32, bool, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, false, err
+	}
+	if parsed.Host == "" {
+		return 0, false, nil
+	}
+	port, err := strconv.ParseInt(parsed.Port(), 10, 32)
+	if err != nil {
+		return 0, false, nil // not every clusterURL-shaped value carries an explicit port
+	}
+	return int32(port), true, nil
+}
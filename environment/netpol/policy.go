@@ -0,0 +1,108 @@
+package netpol
+
+import (
+	"fmt"
+
+	coreV1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+// dnsPort is kube-dns/CoreDNS, allowed as egress from every pod so in-cluster DNS resolution keeps working
+// once a NetworkPolicy locks a pod down.
+const dnsPort = 53
+
+// Synthesize turns a Graph into one NetworkPolicy per destination pod selector, allowing ingress only from the
+// selectors that reference it on the specific port, and one NetworkPolicy per source pod selector allowing
+// egress only to what it consumes plus DNS.
+func Synthesize(graph *Graph, namespace string) []*networkingV1.NetworkPolicy {
+	bySource := map[string][]Edge{}
+	byDest := map[string][]Edge{}
+	selectors := map[string]PodSelector{}
+	for _, edge := range graph.Edges {
+		bySource[edge.From.ID] = append(bySource[edge.From.ID], edge)
+		byDest[edge.To.ID] = append(byDest[edge.To.ID], edge)
+		selectors[edge.From.ID] = edge.From
+		selectors[edge.To.ID] = edge.To
+	}
+
+	var policies []*networkingV1.NetworkPolicy
+	for id, selector := range selectors {
+		policies = append(policies, ingressPolicy(namespace, selector, byDest[id]))
+		policies = append(policies, egressPolicy(namespace, selector, bySource[id]))
+	}
+	return policies
+}
+
+func ingressPolicy(namespace string, selector PodSelector, incoming []Edge) *networkingV1.NetworkPolicy {
+	var rules []networkingV1.NetworkPolicyIngressRule
+	for _, edge := range incoming {
+		rules = append(rules, networkingV1.NetworkPolicyIngressRule{
+			From:  []networkingV1.NetworkPolicyPeer{{PodSelector: &metaV1.LabelSelector{MatchLabels: edge.From.Labels}}},
+			Ports: []networkingV1.NetworkPolicyPort{tcpPort(edge.Port)},
+		})
+	}
+	return &networkingV1.NetworkPolicy{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-ingress", selector.ID),
+			Namespace: namespace,
+		},
+		Spec: networkingV1.NetworkPolicySpec{
+			PodSelector: metaV1.LabelSelector{MatchLabels: selector.Labels},
+			PolicyTypes: []networkingV1.PolicyType{networkingV1.PolicyTypeIngress},
+			Ingress:     rules,
+		},
+	}
+}
+
+func egressPolicy(namespace string, selector PodSelector, outgoing []Edge) *networkingV1.NetworkPolicy {
+	rules := []networkingV1.NetworkPolicyEgressRule{{
+		// Always allow DNS so a locked-down pod can still resolve in-cluster service names.
+		Ports: []networkingV1.NetworkPolicyPort{tcpPort(dnsPort), udpPort(dnsPort)},
+	}}
+	for _, edge := range outgoing {
+		rules = append(rules, networkingV1.NetworkPolicyEgressRule{
+			To:    []networkingV1.NetworkPolicyPeer{{PodSelector: &metaV1.LabelSelector{MatchLabels: edge.To.Labels}}},
+			Ports: []networkingV1.NetworkPolicyPort{tcpPort(edge.Port)},
+		})
+	}
+	return &networkingV1.NetworkPolicy{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-egress", selector.ID),
+			Namespace: namespace,
+		},
+		Spec: networkingV1.NetworkPolicySpec{
+			PodSelector: metaV1.LabelSelector{MatchLabels: selector.Labels},
+			PolicyTypes: []networkingV1.PolicyType{networkingV1.PolicyTypeEgress},
+			Egress:      rules,
+		},
+	}
+}
+
+func tcpPort(port int32) networkingV1.NetworkPolicyPort {
+	protocol := coreV1.ProtocolTCP
+	p := intstr.FromInt(int(port))
+	return networkingV1.NetworkPolicyPort{Protocol: &protocol, Port: &p}
+}
+
+func udpPort(port int32) networkingV1.NetworkPolicyPort {
+	protocol := coreV1.ProtocolUDP
+	p := intstr.FromInt(int(port))
+	return networkingV1.NetworkPolicyPort{Protocol: &protocol, Port: &p}
+}
+
+// DumpYAML renders policies for the offline dry-run mode, one document per policy, so they can be reviewed
+// in CI or checked into a hardening PR without touching a live cluster.
+func DumpYAML(policies []*networkingV1.NetworkPolicy) (string, error) {
+	out := ""
+	for _, policy := range policies {
+		b, err := yaml.Marshal(policy)
+		if err != nil {
+			return "", err
+		}
+		out += "---\n" + string(b)
+	}
+	return out, nil
+}
@@ -0,0 +1,46 @@
+package netpol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func graphForChainlinkAndPostgres() *Graph {
+	return &Graph{Edges: []Edge{{
+		From: PodSelector{ID: "chainlink-0", Labels: map[string]string{"app": "chainlink-0"}},
+		To:   PodSelector{ID: "postgres-0", Labels: map[string]string{"app": "postgres-0"}},
+		Port: 5432,
+	}}}
+}
+
+func TestSynthesize_AllowsTheDiscoveredEdgeAndNothingElse(t *testing.T) {
+	policies := Synthesize(graphForChainlinkAndPostgres(), "basic-chainlink")
+	require.NotEmpty(t, policies)
+
+	allowed := ObservedConnection{
+		FromPodLabels: map[string]string{"app": "chainlink-0"},
+		ToPodLabels:   map[string]string{"app": "postgres-0"},
+		Port:          5432,
+	}
+	assert.NoError(t, Validate(policies, []ObservedConnection{allowed}))
+
+	notInGraph := ObservedConnection{
+		FromPodLabels: map[string]string{"app": "explorer"},
+		ToPodLabels:   map[string]string{"app": "postgres-0"},
+		Port:          5432,
+	}
+	assert.Error(t, Validate(policies, []ObservedConnection{notInGraph}))
+}
+
+func TestValidate_WrongPortIsRejected(t *testing.T) {
+	policies := Synthesize(graphForChainlinkAndPostgres(), "basic-chainlink")
+
+	wrongPort := ObservedConnection{
+		FromPodLabels: map[string]string{"app": "chainlink-0"},
+		ToPodLabels:   map[string]string{"app": "postgres-0"},
+		Port:          9999,
+	}
+	assert.Error(t, Validate(policies, []ObservedConnection{wrongPort}))
+}
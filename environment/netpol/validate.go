@@ -0,0 +1,86 @@
+package netpol
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	networkingV1 "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ObservedConnection is one connection attempt seen during a test run, as reported by whatever traffic probe
+// the environment wires up (e.g. a sidecar or the adapter/chainlink clients themselves).
+type ObservedConnection struct {
+	FromPodLabels map[string]string
+	ToPodLabels   map[string]string
+	Port          int32
+}
+
+// Validate fails with a descriptive error on the first observed connection that none of policies would allow,
+// so a test can assert the computed NetworkPolicy set actually matches the traffic the environment generates
+// before it's applied for real.
+func Validate(policies []*networkingV1.NetworkPolicy, observed []ObservedConnection) error {
+	for _, conn := range observed {
+		if !anyPolicyAllows(policies, conn) {
+			return fmt.Errorf(
+				"connection %v -> %v on port %d is not allowed by any computed NetworkPolicy",
+				conn.FromPodLabels, conn.ToPodLabels, conn.Port,
+			)
+		}
+	}
+	return nil
+}
+
+func anyPolicyAllows(policies []*networkingV1.NetworkPolicy, conn ObservedConnection) bool {
+	for _, policy := range policies {
+		if !labelsMatch(policy.Spec.PodSelector.MatchLabels, conn.ToPodLabels) {
+			continue
+		}
+		for _, rule := range policy.Spec.Ingress {
+			if ingressRuleAllows(rule, conn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func ingressRuleAllows(rule networkingV1.NetworkPolicyIngressRule, conn ObservedConnection) bool {
+	portAllowed := len(rule.Ports) == 0
+	for _, port := range rule.Ports {
+		if port.Port != nil && port.Port.IntVal == conn.Port {
+			portAllowed = true
+		}
+	}
+	if !portAllowed {
+		return false
+	}
+	for _, peer := range rule.From {
+		if peer.PodSelector != nil && labelsMatch(peer.PodSelector.MatchLabels, conn.FromPodLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+func labelsMatch(selector, labels map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply creates or updates every policy in the cluster, used once the dry-run output has been reviewed.
+func Apply(ctx context.Context, client kubernetes.Interface, namespace string, policies []*networkingV1.NetworkPolicy) error {
+	for _, policy := range policies {
+		_, err := client.NetworkingV1().NetworkPolicies(namespace).Create(ctx, policy, metaV1.CreateOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "error applying network policy %s", policy.Name)
+		}
+	}
+	return nil
+}
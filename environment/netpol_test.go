@@ -0,0 +1,34 @@
+package environment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingV1 "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestApplyNetworkPolicies(t *testing.T) {
+	t.Run("errors when no policies were staged for envName", func(t *testing.T) {
+		err := ApplyNetworkPolicies(context.Background(), fake.NewSimpleClientset(), "default", "no-such-env")
+		assert.Error(t, err)
+	})
+
+	t.Run("applies the policies staged under envName", func(t *testing.T) {
+		envName := "netpol-enforce-test"
+		stageComputedPolicies(envName, []*networkingV1.NetworkPolicy{
+			{ObjectMeta: metaV1.ObjectMeta{Name: "allow-adapter-to-postgres"}},
+		})
+
+		client := fake.NewSimpleClientset()
+		require.NoError(t, ApplyNetworkPolicies(context.Background(), client, "default", envName))
+
+		policies, err := client.NetworkingV1().NetworkPolicies("default").List(context.Background(), metaV1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, policies.Items, 1)
+		assert.Equal(t, "allow-adapter-to-postgres", policies.Items[0].Name)
+	})
+}
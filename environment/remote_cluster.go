@@ -0,0 +1,203 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// RemoteClusterLabel marks a Secret in the home cluster as holding the kubeconfig for a remote cluster that
+// chainlink nodes can be scheduled onto. Modeled after the Istio Admiral remote-secret convention.
+const RemoteClusterLabel = "chainlink/cluster=remote"
+
+// RemoteCluster is a single remote Kubernetes cluster a chainlink node can be deployed to, built from the
+// kubeconfig stored in a labeled Secret on the home cluster.
+type RemoteCluster struct {
+	Name      string
+	SecretKey string
+	Client    kubernetes.Interface
+}
+
+// RemoteClusterRegistry watches labeled Secrets on a home cluster and keeps a live client per remote cluster,
+// so K8sEnvSpecs can schedule a Chainlink node's manifests onto whichever cluster its TargetCluster names.
+type RemoteClusterRegistry struct {
+	homeClient kubernetes.Interface
+	namespace  string
+
+	mu       sync.RWMutex
+	clusters map[string]*RemoteCluster
+
+	informer cache.SharedIndexInformer
+	stopCh   chan struct{}
+}
+
+// NewRemoteClusterRegistry builds a registry that watches Secrets labeled with RemoteClusterLabel in namespace
+// on the home cluster, using homeClient to list/watch.
+func NewRemoteClusterRegistry(homeClient kubernetes.Interface, namespace string) *RemoteClusterRegistry {
+	return &RemoteClusterRegistry{
+		homeClient: homeClient,
+		namespace:  namespace,
+		clusters:   map[string]*RemoteCluster{},
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Start begins watching remote-cluster secrets and blocks until the initial list has synced. Call Stop to
+// tear the watch down when the environment is torn down.
+func (r *RemoteClusterRegistry) Start(ctx context.Context) error {
+	watchList := cache.NewListWatchFromClient(
+		r.homeClient.CoreV1().RESTClient(),
+		"secrets",
+		r.namespace,
+		fields.Everything(),
+	)
+	r.informer = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metaV1.ListOptions) (runtime.Object, error) {
+				options.LabelSelector = RemoteClusterLabel
+				return watchList.List(options)
+			},
+			WatchFunc: func(options metaV1.ListOptions) (watch.Interface, error) {
+				options.LabelSelector = RemoteClusterLabel
+				return watchList.Watch(options)
+			},
+		},
+		&coreV1.Secret{},
+		0,
+		cache.Indexers{},
+	)
+
+	_, err := r.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    r.onSecretAddOrUpdate,
+		UpdateFunc: func(_, newObj interface{}) { r.onSecretAddOrUpdate(newObj) },
+		DeleteFunc: r.onSecretDelete,
+	})
+	if err != nil {
+		return errors.Wrap(err, "error registering remote cluster secret handlers")
+	}
+
+	go r.informer.Run(r.stopCh)
+	if !cache.WaitForCacheSync(ctx.Done(), r.informer.HasSynced) {
+		return errors.New("timed out waiting for remote cluster secret informer to sync")
+	}
+	return nil
+}
+
+// Stop ends the secret watch, the registry is unusable afterwards.
+func (r *RemoteClusterRegistry) Stop() {
+	close(r.stopCh)
+}
+
+// Cluster returns the client registered for name, and whether it is currently known.
+func (r *RemoteClusterRegistry) Cluster(name string) (*RemoteCluster, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.clusters[name]
+	return c, ok
+}
+
+// ClientFor resolves the client to deploy manifest to, falling back to the home cluster client when the
+// manifest has no TargetCluster set.
+func (r *RemoteClusterRegistry) ClientFor(targetCluster string) (kubernetes.Interface, error) {
+	if targetCluster == "" {
+		return r.homeClient, nil
+	}
+	c, ok := r.Cluster(targetCluster)
+	if !ok {
+		return nil, fmt.Errorf("no remote cluster registered for target %q", targetCluster)
+	}
+	return c.Client, nil
+}
+
+func (r *RemoteClusterRegistry) onSecretAddOrUpdate(obj interface{}) {
+	secret, ok := obj.(*coreV1.Secret)
+	if !ok {
+		return
+	}
+	kubeconfig, ok := secret.Data["kubeconfig"]
+	if !ok {
+		log.Warn().Str("secret", secret.Name).Msg("remote cluster secret is missing a kubeconfig key")
+		return
+	}
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		log.Err(err).Str("secret", secret.Name).Msg("error parsing kubeconfig from remote cluster secret")
+		return
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		log.Err(err).Str("secret", secret.Name).Msg("error building client for remote cluster secret")
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clusters[secret.Name] = &RemoteCluster{
+		Name:      secret.Name,
+		SecretKey: secret.Name,
+		Client:    client,
+	}
+	log.Info().Str("cluster", secret.Name).Msg("registered remote cluster, credentials rotated if previously known")
+}
+
+// ExternalServiceURL resolves a URL a pod on a different cluster can use to reach svc, preferring a
+// LoadBalancer ingress address and falling back to a NodePort plus the service's first ready node IP.
+// It's used to thread values like postgres.clusterURL or adapter.clusterURL to chainlink nodes that were
+// scheduled onto a remote cluster by a manifest's TargetCluster.
+func ExternalServiceURL(client kubernetes.Interface, namespace, serviceName, scheme string, port int32) (string, error) {
+	svc, err := client.CoreV1().Services(namespace).Get(context.Background(), serviceName, metaV1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "error fetching service %s/%s for cross-cluster URL discovery", namespace, serviceName)
+	}
+
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		host := ingress.IP
+		if host == "" {
+			host = ingress.Hostname
+		}
+		if host != "" {
+			return fmt.Sprintf("%s://%s:%d", scheme, host, port), nil
+		}
+	}
+
+	for _, svcPort := range svc.Spec.Ports {
+		if svcPort.Port != port || svcPort.NodePort == 0 {
+			continue
+		}
+		nodes, err := client.CoreV1().Nodes().List(context.Background(), metaV1.ListOptions{})
+		if err != nil {
+			return "", errors.Wrap(err, "error listing nodes for NodePort cross-cluster URL discovery")
+		}
+		for _, node := range nodes.Items {
+			for _, addr := range node.Status.Addresses {
+				if addr.Type == coreV1.NodeExternalIP || addr.Type == coreV1.NodeInternalIP {
+					return fmt.Sprintf("%s://%s:%d", scheme, addr.Address, svcPort.NodePort), nil
+				}
+			}
+		}
+	}
+
+	return "", fmt.Errorf("service %s/%s has no LoadBalancer ingress or reachable NodePort for port %d", namespace, serviceName, port)
+}
+
+func (r *RemoteClusterRegistry) onSecretDelete(obj interface{}) {
+	secret, ok := obj.(*coreV1.Secret)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.clusters, secret.Name)
+	log.Info().Str("cluster", secret.Name).Msg("remote cluster secret removed, draining nodes targeting it")
+}
@@ -0,0 +1,86 @@
+package environment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClientFor(t *testing.T) {
+	homeClient := fake.NewSimpleClientset()
+	remoteClient := fake.NewSimpleClientset()
+	registry := NewRemoteClusterRegistry(homeClient, "default")
+	registry.clusters["eu-west-1"] = &RemoteCluster{Name: "eu-west-1", Client: remoteClient}
+
+	t.Run("no target cluster falls back to home", func(t *testing.T) {
+		client, err := registry.ClientFor("")
+		require.NoError(t, err)
+		assert.Same(t, homeClient, client)
+	})
+
+	t.Run("known target cluster returns its client", func(t *testing.T) {
+		client, err := registry.ClientFor("eu-west-1")
+		require.NoError(t, err)
+		assert.Same(t, remoteClient, client)
+	})
+
+	t.Run("unknown target cluster errors", func(t *testing.T) {
+		_, err := registry.ClientFor("does-not-exist")
+		assert.Error(t, err)
+	})
+}
+
+func TestExternalServiceURL(t *testing.T) {
+	t.Run("prefers a LoadBalancer ingress IP", func(t *testing.T) {
+		client := fake.NewSimpleClientset(&coreV1.Service{
+			ObjectMeta: metaV1.ObjectMeta{Name: "postgres-0", Namespace: "default"},
+			Spec: coreV1.ServiceSpec{
+				Ports: []coreV1.ServicePort{{Port: 5432, NodePort: 30000}},
+			},
+			Status: coreV1.ServiceStatus{
+				LoadBalancer: coreV1.LoadBalancerStatus{
+					Ingress: []coreV1.LoadBalancerIngress{{IP: "203.0.113.10"}},
+				},
+			},
+		})
+
+		url, err := ExternalServiceURL(client, "default", "postgres-0", "postgresql", 5432)
+		require.NoError(t, err)
+		assert.Equal(t, "postgresql://203.0.113.10:5432", url)
+	})
+
+	t.Run("falls back to a NodePort and node address", func(t *testing.T) {
+		client := fake.NewSimpleClientset(
+			&coreV1.Service{
+				ObjectMeta: metaV1.ObjectMeta{Name: "adapter", Namespace: "default"},
+				Spec: coreV1.ServiceSpec{
+					Ports: []coreV1.ServicePort{{Port: 6060, NodePort: 31060}},
+				},
+			},
+			&coreV1.Node{
+				ObjectMeta: metaV1.ObjectMeta{Name: "node-1"},
+				Status: coreV1.NodeStatus{
+					Addresses: []coreV1.NodeAddress{{Type: coreV1.NodeExternalIP, Address: "198.51.100.5"}},
+				},
+			},
+		)
+
+		url, err := ExternalServiceURL(client, "default", "adapter", "http", 6060)
+		require.NoError(t, err)
+		assert.Equal(t, "http://198.51.100.5:31060", url)
+	})
+
+	t.Run("errors when neither a LoadBalancer nor a usable NodePort exists", func(t *testing.T) {
+		client := fake.NewSimpleClientset(&coreV1.Service{
+			ObjectMeta: metaV1.ObjectMeta{Name: "adapter", Namespace: "default"},
+			Spec:       coreV1.ServiceSpec{Ports: []coreV1.ServicePort{{Port: 6060}}},
+		})
+
+		_, err := ExternalServiceURL(client, "default", "adapter", "http", 6060)
+		assert.Error(t, err)
+	})
+}
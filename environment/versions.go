@@ -0,0 +1,266 @@
+package environment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-github/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
+
+	"github.com/smartcontractkit/integrations-framework/tools"
+)
+
+// versionCacheTTL is how long a resolved version list is trusted before VersionResolver hits GitHub again.
+const versionCacheTTL = 6 * time.Hour
+
+// versionCachePath is where resolved chainlink release versions are cached between test runs, to keep
+// unauthenticated runs under GitHub's 60/hr rate limit.
+var versionCachePath = filepath.Join(tools.ProjectRoot, ".cache", "chainlink-versions.json")
+
+// versionLockPath is an optional pin file that, if present, is used verbatim instead of hitting GitHub at
+// all, so CI can pin an exact, reviewed set of versions for reproducible mixed-version runs.
+var versionLockPath = filepath.Join(tools.ProjectRoot, "chainlink-versions.lock")
+
+// fallbackVersions is used when GitHub is unreachable and no cache or lock file is available, so a mixed
+// version deploy degrades to a known-good set rather than failing outright.
+var fallbackVersions = []string{"1.9.0", "1.8.1", "1.7.1"}
+
+// VersionResolver resolves the set of past chainlink versions NewMixedVersionChainlinkCluster mixes in
+// alongside the current dev image.
+type VersionResolver interface {
+	// Resolve returns up to count versions (without a leading "v"), newest first.
+	Resolve(ctx context.Context, count int) ([]string, error)
+}
+
+// VersionConstraint selects which releases a githubVersionResolver considers eligible.
+type VersionConstraint struct {
+	// SemverRange is a Masterminds/semver constraint string, e.g. ">=1.10.0, <2.0.0". Empty means no range
+	// restriction.
+	SemverRange string
+	// ExcludePrerelease skips tags with a semver prerelease component (e.g. "1.11.0-beta1").
+	ExcludePrerelease bool
+	// LatestPatchPerMinor keeps only the newest patch release for each minor version, so "latest N minors"
+	// selection doesn't return multiple patches of the same minor.
+	LatestPatchPerMinor bool
+}
+
+// githubVersionResolver is the default VersionResolver: GitHub releases, filtered by constraint, with an
+// on-disk cache, an optional reproducible lock file, and a hard-coded fallback if GitHub is unreachable.
+type githubVersionResolver struct {
+	client     *github.Client
+	constraint VersionConstraint
+}
+
+// NewVersionResolver builds the default VersionResolver. githubToken may be empty, in which case requests
+// are unauthenticated and subject to GitHub's 60/hr rate limit for anonymous clients.
+func NewVersionResolver(githubToken string, constraint VersionConstraint) VersionResolver {
+	var httpClient = http.DefaultClient
+	if githubToken != "" {
+		httpClient = oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(
+			&oauth2.Token{AccessToken: githubToken},
+		))
+	}
+	return &githubVersionResolver{
+		client:     github.NewClient(httpClient),
+		constraint: constraint,
+	}
+}
+
+// Resolve implements VersionResolver. It checks the lock file first, then a fresh cache entry matching this
+// resolver's constraint, then GitHub, then the hard-coded fallback (itself filtered by the constraint), in
+// that order, writing a successful GitHub fetch back to the cache.
+func (g *githubVersionResolver) Resolve(ctx context.Context, count int) ([]string, error) {
+	if locked, ok := readLockFile(); ok {
+		log.Info().Strs("versions", locked).Msg("using pinned chainlink-versions.lock for mixed version selection")
+		return truncate(locked, count), nil
+	}
+
+	if cached, ok := readCache(g.constraint); ok {
+		return truncate(cached, count), nil
+	}
+
+	versions, err := g.fetchFromGitHub(ctx)
+	if err != nil {
+		log.Err(err).Msg("error resolving chainlink versions from github, falling back to hard-coded versions")
+		fallback, ferr := filterVersionsByConstraint(fallbackVersions, g.constraint)
+		if ferr != nil {
+			return nil, ferr
+		}
+		if len(fallback) == 0 {
+			return nil, fmt.Errorf("no hard-coded fallback chainlink versions matched constraint %q", g.constraint.SemverRange)
+		}
+		return truncate(fallback, count), nil
+	}
+
+	if err := writeCache(g.constraint, versions); err != nil {
+		log.Err(err).Msg("error writing chainlink version cache, continuing without it")
+	}
+	return truncate(versions, count), nil
+}
+
+// fetchFromGitHub returns every release matching g.constraint, newest first, untruncated — Resolve caches this
+// full set and truncates only at the point of returning to the caller, so a later call asking for a larger
+// count within the cache TTL isn't silently starved by an earlier call's smaller count.
+func (g *githubVersionResolver) fetchFromGitHub(ctx context.Context) ([]string, error) {
+	var allReleases []*github.RepositoryRelease
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		releases, resp, err := g.client.Repositories.ListReleases(ctx, "smartcontractkit", "chainlink", opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "error listing chainlink releases from github")
+		}
+		allReleases = append(allReleases, releases...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	var tags []string
+	for _, release := range allReleases {
+		if release.GetDraft() {
+			continue
+		}
+		tags = append(tags, release.GetTagName())
+	}
+
+	versions, err := filterVersionsByConstraint(tags, g.constraint)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no chainlink releases matched constraint %q", g.constraint.SemverRange)
+	}
+	return versions, nil
+}
+
+// filterVersionsByConstraint parses tags as semver (skipping tags that aren't valid semver, e.g. one-off
+// hotfix branches), applies vc, and returns the survivors sorted newest first. Used for both live GitHub tags
+// and the hard-coded fallback list, so a caller's constraint is honored no matter which source served it.
+func filterVersionsByConstraint(tags []string, vc VersionConstraint) ([]string, error) {
+	var constraint *semver.Constraints
+	if vc.SemverRange != "" {
+		c, err := semver.NewConstraint(vc.SemverRange)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid semver constraint %q", vc.SemverRange)
+		}
+		constraint = c
+	}
+
+	var parsed []*semver.Version
+	for _, tag := range tags {
+		v, err := semver.NewVersion(strings.TrimPrefix(tag, "v"))
+		if err != nil {
+			continue
+		}
+		if vc.ExcludePrerelease && v.Prerelease() != "" {
+			continue
+		}
+		if constraint != nil && !constraint.Check(v) {
+			continue
+		}
+		parsed = append(parsed, v)
+	}
+
+	sort.Sort(sort.Reverse(semver.Collection(parsed)))
+
+	if vc.LatestPatchPerMinor {
+		parsed = latestPatchPerMinor(parsed)
+	}
+
+	versions := make([]string, 0, len(parsed))
+	for _, v := range parsed {
+		versions = append(versions, v.Original())
+	}
+	return versions, nil
+}
+
+func latestPatchPerMinor(versions []*semver.Version) []*semver.Version {
+	seen := map[string]bool{}
+	var result []*semver.Version
+	for _, v := range versions { // versions is already sorted newest-first
+		minorKey := fmt.Sprintf("%d.%d", v.Major(), v.Minor())
+		if seen[minorKey] {
+			continue
+		}
+		seen[minorKey] = true
+		result = append(result, v)
+	}
+	return result
+}
+
+func truncate(versions []string, count int) []string {
+	if count >= 0 && len(versions) > count {
+		return versions[:count]
+	}
+	return versions
+}
+
+type versionCacheFile struct {
+	ResolvedAt time.Time         `json:"resolved_at"`
+	Constraint VersionConstraint `json:"constraint"`
+	Versions   []string          `json:"versions"`
+}
+
+// readCache returns the cached versions only if they're within TTL and were resolved under the same
+// constraint being asked for now — a cache entry from a resolver with a different VersionConstraint is
+// treated as a miss rather than handed back, since it may violate this resolver's own constraint.
+func readCache(constraint VersionConstraint) ([]string, bool) {
+	b, err := os.ReadFile(versionCachePath)
+	if err != nil {
+		return nil, false
+	}
+	var cache versionCacheFile
+	if err := json.Unmarshal(b, &cache); err != nil {
+		return nil, false
+	}
+	if time.Since(cache.ResolvedAt) > versionCacheTTL {
+		return nil, false
+	}
+	if cache.Constraint != constraint {
+		return nil, false
+	}
+	return cache.Versions, true
+}
+
+func writeCache(constraint VersionConstraint, versions []string) error {
+	if err := os.MkdirAll(filepath.Dir(versionCachePath), 0o755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(versionCacheFile{ResolvedAt: time.Now(), Constraint: constraint, Versions: versions})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(versionCachePath, b, 0o644)
+}
+
+// readLockFile reads chainlink-versions.lock, one version per line, blank lines and "#" comments ignored.
+// Its presence takes priority over both the cache and a live GitHub lookup, for reproducible CI runs.
+func readLockFile() ([]string, bool) {
+	b, err := os.ReadFile(versionLockPath)
+	if err != nil {
+		return nil, false
+	}
+	var versions []string
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		versions = append(versions, strings.TrimPrefix(line, "v"))
+	}
+	if len(versions) == 0 {
+		return nil, false
+	}
+	return versions, true
+}
@@ -0,0 +1,128 @@
+package environment
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterVersionsByConstraint(t *testing.T) {
+	tags := []string{"v1.11.0", "v1.10.2", "v1.10.1", "v1.9.0-beta1", "v1.9.0", "not-a-version"}
+
+	t.Run("no constraint returns every valid semver tag, newest first", func(t *testing.T) {
+		versions, err := filterVersionsByConstraint(tags, VersionConstraint{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1.11.0", "1.10.2", "1.10.1", "1.9.0-beta1", "1.9.0"}, versions)
+	})
+
+	t.Run("semver range excludes out-of-range tags", func(t *testing.T) {
+		versions, err := filterVersionsByConstraint(tags, VersionConstraint{SemverRange: ">=1.10.0, <1.11.0"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1.10.2", "1.10.1"}, versions)
+	})
+
+	t.Run("ExcludePrerelease drops prerelease tags", func(t *testing.T) {
+		versions, err := filterVersionsByConstraint(tags, VersionConstraint{ExcludePrerelease: true})
+		require.NoError(t, err)
+		assert.NotContains(t, versions, "1.9.0-beta1")
+	})
+
+	t.Run("LatestPatchPerMinor keeps only the newest patch per minor", func(t *testing.T) {
+		versions, err := filterVersionsByConstraint(tags, VersionConstraint{LatestPatchPerMinor: true, ExcludePrerelease: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1.11.0", "1.10.2", "1.9.0"}, versions)
+	})
+
+	t.Run("invalid semver range errors", func(t *testing.T) {
+		_, err := filterVersionsByConstraint(tags, VersionConstraint{SemverRange: "not a range"})
+		assert.Error(t, err)
+	})
+
+	t.Run("a constraint matching nothing returns an empty, non-nil-error result", func(t *testing.T) {
+		versions, err := filterVersionsByConstraint(tags, VersionConstraint{SemverRange: ">=99.0.0"})
+		require.NoError(t, err)
+		assert.Empty(t, versions)
+	})
+}
+
+func TestLatestPatchPerMinor(t *testing.T) {
+	versions := []*semver.Version{
+		semver.MustParse("1.11.0"),
+		semver.MustParse("1.10.2"),
+		semver.MustParse("1.10.1"),
+		semver.MustParse("1.9.0"),
+	}
+	result := latestPatchPerMinor(versions)
+	require.Len(t, result, 3)
+	assert.Equal(t, "1.11.0", result[0].Original())
+	assert.Equal(t, "1.10.2", result[1].Original())
+	assert.Equal(t, "1.9.0", result[2].Original())
+}
+
+func TestTruncate(t *testing.T) {
+	versions := []string{"1.11.0", "1.10.2", "1.9.0"}
+	assert.Equal(t, []string{"1.11.0", "1.10.2"}, truncate(versions, 2))
+	assert.Equal(t, versions, truncate(versions, 10))
+	assert.Equal(t, versions, truncate(versions, -1))
+}
+
+func TestReadWriteCache(t *testing.T) {
+	dir := t.TempDir()
+	originalPath := versionCachePath
+	versionCachePath = filepath.Join(dir, "chainlink-versions.json")
+	defer func() { versionCachePath = originalPath }()
+
+	constraint := VersionConstraint{SemverRange: ">=1.10.0"}
+	require.NoError(t, writeCache(constraint, []string{"1.11.0", "1.10.2"}))
+
+	t.Run("a fresh cache entry for the same constraint is returned", func(t *testing.T) {
+		versions, ok := readCache(constraint)
+		require.True(t, ok)
+		assert.Equal(t, []string{"1.11.0", "1.10.2"}, versions)
+	})
+
+	t.Run("a different constraint is treated as a cache miss", func(t *testing.T) {
+		_, ok := readCache(VersionConstraint{SemverRange: ">=2.0.0"})
+		assert.False(t, ok)
+	})
+
+	t.Run("an expired entry is treated as a cache miss", func(t *testing.T) {
+		expired := versionCacheFile{
+			ResolvedAt: time.Now().Add(-versionCacheTTL - time.Hour),
+			Constraint: constraint,
+			Versions:   []string{"1.11.0"},
+		}
+		b, err := json.Marshal(expired)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(versionCachePath, b, 0o644))
+
+		_, ok := readCache(constraint)
+		assert.False(t, ok)
+	})
+}
+
+func TestReadLockFile(t *testing.T) {
+	dir := t.TempDir()
+	originalPath := versionLockPath
+	versionLockPath = filepath.Join(dir, "chainlink-versions.lock")
+	defer func() { versionLockPath = originalPath }()
+
+	t.Run("missing lock file is not ok", func(t *testing.T) {
+		_, ok := readLockFile()
+		assert.False(t, ok)
+	})
+
+	t.Run("parses versions, skipping blanks, comments, and a leading v", func(t *testing.T) {
+		content := "# pinned chainlink versions\nv1.11.0\n\n1.10.2\n"
+		require.NoError(t, os.WriteFile(versionLockPath, []byte(content), 0o644))
+		versions, ok := readLockFile()
+		require.True(t, ok)
+		assert.Equal(t, []string{"1.11.0", "1.10.2"}, versions)
+	})
+}